@@ -6,9 +6,14 @@ import (
 	"os"
 
 	"go-chi-restful/routes"
+	"go-chi-restful/routes/observability"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func main() {
@@ -20,6 +25,17 @@ func main() {
 
 	log.Printf("Starting up on http://localhost:%s", port)
 
+	tp := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(tp)
+
+	registry := prometheus.NewRegistry()
+	metrics := observability.NewMetrics(registry)
+
+	client := observability.InstrumentClient(routes.NewJSONPlaceholderClient(nil), tp)
+	postsResource := routes.NewPostsResource(client)
+	postsResource.Middleware = observability.Middleware(tp, metrics)
+	postsResource.AttachmentStore = routes.NewLocalAttachmentStore("./attachments")
+
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
@@ -28,7 +44,8 @@ func main() {
 		w.Write([]byte("Hello World!"))
 	})
 
-	r.Mount("/posts", routes.PostsResource{}.Routes())
+	r.Mount("/posts", postsResource.Routes())
+	r.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
 	log.Fatal(http.ListenAndServe(":"+port, r))
 }
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalAttachmentStore saves attachments under Dir/<postID>/<filename> on
+// the local disk. It is the default AttachmentStore used outside of S3
+// deployments.
+type LocalAttachmentStore struct {
+	// Dir is the root directory attachments are written under. Save
+	// creates it, along with the per-post subdirectory, if it is missing.
+	Dir string
+}
+
+// NewLocalAttachmentStore returns a LocalAttachmentStore rooted at dir.
+func NewLocalAttachmentStore(dir string) *LocalAttachmentStore {
+	return &LocalAttachmentStore{Dir: dir}
+}
+
+func (s *LocalAttachmentStore) Save(ctx context.Context, postID, filename, contentType string, r io.Reader) (string, error) {
+	dir := filepath.Join(s.Dir, postID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating attachment directory: %w", err)
+	}
+
+	path := filepath.Join(dir, filename)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating attachment file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("writing attachment file: %w", err)
+	}
+
+	return "/attachments/" + postID + "/" + filename, nil
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// S3AttachmentStore is a stub shaped for a future AWS SDK-backed
+// implementation: attachments would be written to Bucket under
+// <Prefix>/<postID>/<filename>. It is wired up so PostsResource can be
+// pointed at it once the real upload is implemented, without another
+// interface change.
+type S3AttachmentStore struct {
+	Bucket string
+	Prefix string
+}
+
+// NewS3AttachmentStore returns an S3AttachmentStore targeting bucket,
+// storing objects under prefix.
+func NewS3AttachmentStore(bucket, prefix string) *S3AttachmentStore {
+	return &S3AttachmentStore{Bucket: bucket, Prefix: prefix}
+}
+
+func (s *S3AttachmentStore) Save(ctx context.Context, postID, filename, contentType string, r io.Reader) (string, error) {
+	return "", errors.New("routes: S3AttachmentStore is not yet implemented")
+}
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// AttachmentStore persists a single uploaded file for a post and returns a
+// URL the client can use to retrieve it.
+type AttachmentStore interface {
+	Save(ctx context.Context, postID, filename, contentType string, r io.Reader) (url string, err error)
+}
+
+const (
+	// maxAttachmentRequestBytes caps the overall multipart request body.
+	maxAttachmentRequestBytes = 32 << 20 // 32MiB
+
+	// maxAttachmentFileBytes caps a single uploaded file.
+	maxAttachmentFileBytes = 8 << 20 // 8MiB
+
+	// maxAttachmentFiles caps the number of files accepted per request.
+	maxAttachmentFiles = 10
+)
+
+// errFileTooLarge is returned by the capped reader passed to
+// AttachmentStore.Save when a file exceeds maxAttachmentFileBytes;
+// attachmentPartError maps it to a 413 response.
+var errFileTooLarge = errors.New("attachment exceeds the maximum allowed file size")
+
+// attachment describes one file saved by CreateAttachment.
+type attachment struct {
+	Filename string `json:"filename"`
+	URL      string `json:"url"`
+}
+
+// CreateAttachment handles POST /posts/{id}/attachments: a
+// multipart/form-data upload of a name, a caption, and up to
+// maxAttachmentFiles image files, forwarded to rs.AttachmentStore.
+func (rs *PostsResource) CreateAttachment(w http.ResponseWriter, r *http.Request) {
+	if rs.AttachmentStore == nil {
+		respondError(w, NewHTTPError(http.StatusInternalServerError, "attachment store not configured"))
+		return
+	}
+
+	postID := chi.URLParam(r, "id")
+
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		respondError(w, NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be multipart/form-data"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentRequestBytes)
+	reader := multipart.NewReader(r.Body, params["boundary"])
+
+	var name, caption string
+	var saved []attachment
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			respondError(w, attachmentPartError(err))
+			return
+		}
+
+		switch part.FormName() {
+		case "name":
+			name, err = readFormValue(part)
+		case "caption":
+			caption, err = readFormValue(part)
+		case "files":
+			if len(saved) >= maxAttachmentFiles {
+				err = NewHTTPError(http.StatusBadRequest, "too many files")
+				break
+			}
+
+			var saved1 attachment
+			saved1, err = rs.saveAttachmentPart(r.Context(), postID, part)
+			if err == nil {
+				saved = append(saved, saved1)
+			}
+		}
+
+		part.Close()
+
+		if err != nil {
+			respondError(w, attachmentPartError(err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Name        string       `json:"name"`
+		Caption     string       `json:"caption"`
+		Attachments []attachment `json:"attachments"`
+	}{Name: name, Caption: caption, Attachments: saved})
+}
+
+// saveAttachmentPart validates a "files" part's Content-Type against the
+// image/* whitelist and streams it to rs.AttachmentStore, capping it at
+// maxAttachmentFileBytes without buffering it in memory.
+func (rs *PostsResource) saveAttachmentPart(ctx context.Context, postID string, part *multipart.Part) (attachment, error) {
+	contentType := part.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return attachment{}, NewHTTPError(http.StatusUnsupportedMediaType, "attachment Content-Type must be image/*")
+	}
+
+	url, err := rs.AttachmentStore.Save(ctx, postID, part.FileName(), contentType, &cappedReader{r: part, n: maxAttachmentFileBytes})
+	if err != nil {
+		return attachment{}, err
+	}
+
+	return attachment{Filename: part.FileName(), URL: url}, nil
+}
+
+// attachmentPartError maps a multipart-parsing error to an *HTTPError,
+// preserving HTTPError and body-size errors and defaulting the rest to
+// 400.
+func attachmentPartError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var httpErr *HTTPError
+
+	switch {
+	case errors.As(err, &maxBytesErr), errors.Is(err, errFileTooLarge):
+		return NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+	case errors.As(err, &httpErr):
+		return err
+	default:
+		return NewHTTPError(http.StatusBadRequest, "malformed multipart body: "+err.Error())
+	}
+}
+
+// readFormValue reads a non-file form field's value, capping it well
+// below maxAttachmentFileBytes since it is held in memory.
+func readFormValue(part *multipart.Part) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(part, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// cappedReader errors with errFileTooLarge once more than n bytes have
+// been read, so AttachmentStore.Save can stream a file without buffering
+// it while still enforcing a size limit. A file of exactly n bytes reads
+// through cleanly to EOF; mirrors http.MaxBytesReader's "read one past
+// the limit to tell a clean EOF from an overflow" approach rather than
+// erroring as soon as the budget hits zero, which would also reject a
+// reader's harmless final zero-byte, io.EOF-only call.
+type cappedReader struct {
+	r   io.Reader
+	n   int64 // bytes still allowed, not counting the one-byte overflow probe
+	err error // sticky error once n has been exceeded
+}
+
+func (c *cappedReader) Read(p []byte) (n int, err error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if int64(len(p)) > c.n+1 {
+		p = p[:c.n+1]
+	}
+
+	n, err = c.r.Read(p)
+
+	if int64(n) <= c.n {
+		c.n -= int64(n)
+		c.err = err
+		return n, err
+	}
+
+	n = int(c.n)
+	c.n = 0
+	c.err = errFileTooLarge
+
+	return n, c.err
+}
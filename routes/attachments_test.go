@@ -0,0 +1,211 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// memoryAttachmentStore is a test AttachmentStore that records every
+// saved file's bytes, keyed by filename.
+type memoryAttachmentStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryAttachmentStore() *memoryAttachmentStore {
+	return &memoryAttachmentStore{files: make(map[string][]byte)}
+}
+
+func (s *memoryAttachmentStore) Save(ctx context.Context, postID, filename, contentType string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.files[filename] = data
+	s.mu.Unlock()
+
+	return "/attachments/" + postID + "/" + filename, nil
+}
+
+func newAttachmentRequest(t *testing.T, fields map[string]string, fileField, filename, fileContentType string, fileBody []byte) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			t.Fatalf("Error writing form field: %v", err)
+		}
+	}
+
+	if fileField != "" {
+		header := make(map[string][]string)
+		header["Content-Disposition"] = []string{`form-data; name="` + fileField + `"; filename="` + filename + `"`}
+		header["Content-Type"] = []string{fileContentType}
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			t.Fatalf("Error creating form file: %v", err)
+		}
+		if _, err := part.Write(fileBody); err != nil {
+			t.Fatalf("Error writing form file: %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing multipart writer: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", "/1/attachments", &buf)
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	return req
+}
+
+func TestCreateAttachment(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryAttachmentStore()
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = store
+
+	req := newAttachmentRequest(t, map[string]string{
+		"name":    "diagram",
+		"caption": "a nice picture",
+	}, "files", "photo.png", "image/png", []byte("fake-png-bytes"))
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code. Expected: %d. Got: %d. Body: %s", http.StatusOK, status, rr.Body.String())
+	}
+
+	var result struct {
+		Name        string `json:"name"`
+		Caption     string `json:"caption"`
+		Attachments []attachment
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("Error decoding response body: %v", err)
+	}
+
+	if result.Name != "diagram" || result.Caption != "a nice picture" {
+		t.Errorf("Unexpected name/caption in response: %+v", result)
+	}
+	if len(result.Attachments) != 1 || result.Attachments[0].Filename != "photo.png" {
+		t.Errorf("Expected one saved attachment named photo.png. Got: %+v", result.Attachments)
+	}
+	if string(store.files["photo.png"]) != "fake-png-bytes" {
+		t.Errorf("Attachment bytes not saved correctly. Got: %q", store.files["photo.png"])
+	}
+}
+
+func TestCreateAttachmentRejectsNonNumericPostID(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryAttachmentStore()
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = store
+
+	req := newAttachmentRequest(t, nil, "files", "photo.png", "image/png", []byte("fake-png-bytes"))
+	req.URL.Path = "/../attachments"
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code for a path-traversal post ID. Expected: %d. Got: %d.", http.StatusNotFound, status)
+	}
+	if len(store.files) != 0 {
+		t.Errorf("Expected no attachment to be saved for a rejected post ID. Got: %+v", store.files)
+	}
+}
+
+func TestCreateAttachmentWrongContentType(t *testing.T) {
+	t.Parallel()
+
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = newMemoryAttachmentStore()
+
+	req, err := http.NewRequest("POST", "/1/attachments", bytes.NewBufferString("not multipart"))
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnsupportedMediaType {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusUnsupportedMediaType, status)
+	}
+}
+
+func TestCreateAttachmentOversizedFile(t *testing.T) {
+	t.Parallel()
+
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = newMemoryAttachmentStore()
+
+	oversized := bytes.Repeat([]byte("a"), maxAttachmentFileBytes+1)
+	req := newAttachmentRequest(t, nil, "files", "big.png", "image/png", oversized)
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusRequestEntityTooLarge, status)
+	}
+}
+
+func TestCreateAttachmentExactlyMaxFileSize(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryAttachmentStore()
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = store
+
+	exact := bytes.Repeat([]byte("a"), maxAttachmentFileBytes)
+	req := newAttachmentRequest(t, nil, "files", "exact.png", "image/png", exact)
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code for a file at exactly the size cap. Expected: %d. Got: %d. Body: %s", http.StatusOK, status, rr.Body.String())
+	}
+
+	if len(store.files["exact.png"]) != maxAttachmentFileBytes {
+		t.Errorf("Expected %d bytes saved. Got: %d.", maxAttachmentFileBytes, len(store.files["exact.png"]))
+	}
+}
+
+func TestCreateAttachmentDisallowedMIME(t *testing.T) {
+	t.Parallel()
+
+	resource := NewPostsResource(nil)
+	resource.AttachmentStore = newMemoryAttachmentStore()
+
+	req := newAttachmentRequest(t, nil, "files", "script.js", "application/javascript", []byte("alert(1)"))
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnsupportedMediaType {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusUnsupportedMediaType, status)
+	}
+}
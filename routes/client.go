@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// baseURL is the upstream JSONPlaceholder API that the real Client
+// implementation talks to.
+const baseURL = "https://jsonplaceholder.typicode.com"
+
+// Client abstracts the upstream posts API so that PostsResource can be
+// exercised without reaching the network. JSONPlaceholderClient is the
+// production implementation; routestest provides a swappable
+// http.RoundTripper for tests. Every method takes the inbound request's
+// context so instrumentation (see observability.InstrumentClient) can
+// start client spans as children of the server span instead of roots.
+type Client interface {
+	GetPosts(ctx context.Context) (*http.Response, error)
+	GetPost(ctx context.Context, id string) (*http.Response, error)
+	CreatePost(ctx context.Context, body io.Reader) (*http.Response, error)
+	UpdatePost(ctx context.Context, id string, body io.Reader) (*http.Response, error)
+	DeletePost(ctx context.Context, id string) (*http.Response, error)
+}
+
+// JSONPlaceholderClient is the Client implementation backed by the real
+// https://jsonplaceholder.typicode.com API. Its http.Client is exported
+// so callers (and tests, via routestest) can swap the RoundTripper.
+type JSONPlaceholderClient struct {
+	HTTPClient *http.Client
+}
+
+// NewJSONPlaceholderClient returns a JSONPlaceholderClient. When
+// httpClient is nil, http.DefaultClient is used.
+func NewJSONPlaceholderClient(httpClient *http.Client) *JSONPlaceholderClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &JSONPlaceholderClient{HTTPClient: httpClient}
+}
+
+func (c *JSONPlaceholderClient) GetPosts(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/posts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.HTTPClient.Do(req)
+}
+
+func (c *JSONPlaceholderClient) GetPost(ctx context.Context, id string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/posts/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.HTTPClient.Do(req)
+}
+
+func (c *JSONPlaceholderClient) CreatePost(ctx context.Context, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/posts", body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.HTTPClient.Do(req)
+}
+
+func (c *JSONPlaceholderClient) UpdatePost(ctx context.Context, id string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, baseURL+"/posts/"+id, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.HTTPClient.Do(req)
+}
+
+func (c *JSONPlaceholderClient) DeletePost(ctx context.Context, id string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, baseURL+"/posts/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.HTTPClient.Do(req)
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// maxRequestBodyBytes caps the size of a decoded request body.
+const maxRequestBodyBytes = 1 << 20 // 1MiB
+
+// decodeJSON decodes the JSON request body into dst, enforcing a
+// Content-Type of application/json and a maximum body size. dst must be
+// a non-nil pointer; this guards against the classic footgun of decoding
+// into a nil *T (e.g. `var p *Post; decodeJSON(w, r, p)`), which
+// json.Decode would otherwise fail on with an opaque InvalidUnmarshalError.
+// Failures are returned as *HTTPError so callers can translate them
+// directly into a status code.
+func decodeJSON[T any](w http.ResponseWriter, r *http.Request, dst *T) error {
+	if dst == nil {
+		return NewHTTPError(http.StatusInternalServerError, "decodeJSON: dst must not be nil")
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+		}
+
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("malformed request body: %v", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package routes
+
+// HTTPError is an error that carries the HTTP status code a handler
+// should respond with, so validation failures deep in a helper (like
+// decodeJSON) can bubble up to the handler without losing that intent.
+type HTTPError struct {
+	Status  int
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// NewHTTPError returns an HTTPError with the given status and message.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
@@ -0,0 +1,59 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"go-chi-restful/routes"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentClient wraps next so every outbound call to the JSONPlaceholder
+// API is wrapped in a client span (named "Client.<Method>", with an
+// http.status_code attribute once the upstream responds).
+func InstrumentClient(next routes.Client, tp trace.TracerProvider) routes.Client {
+	return &instrumentedClient{next: next, tracer: tp.Tracer("go-chi-restful/routes")}
+}
+
+type instrumentedClient struct {
+	next   routes.Client
+	tracer trace.Tracer
+}
+
+func (c *instrumentedClient) call(ctx context.Context, name string, do func() (*http.Response, error)) (*http.Response, error) {
+	_, span := c.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	resp, err := do()
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	return resp, nil
+}
+
+func (c *instrumentedClient) GetPosts(ctx context.Context) (*http.Response, error) {
+	return c.call(ctx, "Client.GetPosts", func() (*http.Response, error) { return c.next.GetPosts(ctx) })
+}
+
+func (c *instrumentedClient) GetPost(ctx context.Context, id string) (*http.Response, error) {
+	return c.call(ctx, "Client.GetPost", func() (*http.Response, error) { return c.next.GetPost(ctx, id) })
+}
+
+func (c *instrumentedClient) CreatePost(ctx context.Context, body io.Reader) (*http.Response, error) {
+	return c.call(ctx, "Client.CreatePost", func() (*http.Response, error) { return c.next.CreatePost(ctx, body) })
+}
+
+func (c *instrumentedClient) UpdatePost(ctx context.Context, id string, body io.Reader) (*http.Response, error) {
+	return c.call(ctx, "Client.UpdatePost", func() (*http.Response, error) { return c.next.UpdatePost(ctx, id, body) })
+}
+
+func (c *instrumentedClient) DeletePost(ctx context.Context, id string) (*http.Response, error) {
+	return c.call(ctx, "Client.DeletePost", func() (*http.Response, error) { return c.next.DeletePost(ctx, id) })
+}
@@ -0,0 +1,96 @@
+// Package observability provides chi middleware and a Client decorator
+// that instrument PostsResource with OpenTelemetry spans and Prometheus
+// metrics, so the REST wrapper can be operated rather than just tested.
+package observability
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics holds the Prometheus collectors recorded per request. NewMetrics
+// registers them against reg; callers expose reg (or
+// prometheus.DefaultRegisterer) at /metrics via promhttp.
+type Metrics struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewMetrics registers the http_requests_total counter and
+// http_request_duration_seconds histogram, both labeled by route, method
+// and status, against reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests handled, labeled by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "http_request_duration_seconds",
+			Help: "HTTP request duration in seconds, labeled by route, method and status.",
+		}, []string{"route", "method", "status"}),
+	}
+
+	reg.MustRegister(m.requestsTotal, m.requestDuration)
+
+	return m
+}
+
+// Middleware returns chi middleware that starts a server span per request
+// (named "<method> <route>", with http.method, http.route and
+// http.status_code attributes) and records it against metrics. tp is
+// injectable so tests can supply a TracerProvider backed by an in-memory
+// exporter.
+func Middleware(tp trace.TracerProvider, metrics *Metrics) func(http.Handler) http.Handler {
+	tracer := tp.Tracer("go-chi-restful/routes")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx, span := tracer.Start(r.Context(), r.Method+" "+r.URL.Path, trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+			span.SetAttributes(attribute.String("http.method", r.Method))
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			route := chi.RouteContext(r.Context()).RoutePattern()
+			if route == "" {
+				route = r.URL.Path
+			}
+
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(
+				attribute.String("http.route", route),
+				attribute.Int("http.status_code", rec.status),
+			)
+			if rec.status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(rec.status))
+			}
+
+			status := strconv.Itoa(rec.status)
+			metrics.requestsTotal.WithLabelValues(route, r.Method, status).Inc()
+			metrics.requestDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// statusRecorder captures the status code passed to WriteHeader so it can
+// be attached to the span and metrics after the handler runs.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
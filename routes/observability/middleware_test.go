@@ -0,0 +1,79 @@
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-chi-restful/routes"
+	"go-chi-restful/routes/routestest"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestGetPostsHandlerEmitsOneServerAndOneClientSpan extends
+// TestGetPostsHandler to verify that a single request through an
+// instrumented PostsResource produces exactly one server span (from
+// Middleware) and one client span (from InstrumentClient).
+func TestGetPostsHandlerEmitsOneServerAndOneClientSpan(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("GET", "/posts", routestest.NewJSONResponder(http.StatusOK, []routes.Post{}))
+
+	client := InstrumentClient(routes.NewJSONPlaceholderClient(&http.Client{Transport: transport}), tp)
+	resource := routes.NewPostsResource(client)
+	resource.Middleware = Middleware(tp, NewMetrics(prometheus.NewRegistry()))
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusOK, status)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans (1 server, 1 client). Got: %d.", len(spans))
+	}
+
+	var serverSpan, clientSpan tracetest.SpanStub
+	var serverSpans, clientSpans int
+	for _, span := range spans {
+		switch span.SpanKind {
+		case trace.SpanKindServer:
+			serverSpans++
+			serverSpan = span
+		case trace.SpanKindClient:
+			clientSpans++
+			clientSpan = span
+		}
+	}
+
+	if serverSpans != 1 {
+		t.Errorf("Expected 1 server span. Got: %d.", serverSpans)
+	}
+	if clientSpans != 1 {
+		t.Errorf("Expected 1 client span. Got: %d.", clientSpans)
+	}
+
+	if clientSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Errorf("Expected the client span to be a child of the server span. Client parent span ID: %s. Server span ID: %s.",
+			clientSpan.Parent.SpanID(), serverSpan.SpanContext.SpanID())
+	}
+	if clientSpan.SpanContext.TraceID() != serverSpan.SpanContext.TraceID() {
+		t.Errorf("Expected the client and server spans to share a trace ID. Client: %s. Server: %s.",
+			clientSpan.SpanContext.TraceID(), serverSpan.SpanContext.TraceID())
+	}
+}
@@ -0,0 +1,182 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
+// Post is a JSONPlaceholder post.
+type Post struct {
+	Id     int    `json:"id"`
+	UserId int    `json:"userId"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// PostWithoutId is the payload accepted for creating or updating a Post;
+// the id is assigned upstream.
+type PostWithoutId struct {
+	UserId int    `json:"userId"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// PostsResource exposes the JSONPlaceholder posts API under /posts. The
+// upstream is reached through the injected Client rather than a
+// package-scoped variable, so callers (and tests) can swap in a fake
+// implementation per-instance instead of mutating global state.
+type PostsResource struct {
+	Client Client
+
+	// Middleware, if set, is installed on the sub-router returned by
+	// Routes() before any routes are registered (chi middleware must be
+	// added before routing). It is typically observability.Middleware.
+	Middleware func(http.Handler) http.Handler
+
+	// AttachmentStore, if set, backs the /{id}/attachments upload
+	// endpoint. CreateAttachment responds 500 if it is nil.
+	AttachmentStore AttachmentStore
+}
+
+// NewPostsResource wires a PostsResource to the given Client.
+func NewPostsResource(client Client) *PostsResource {
+	return &PostsResource{Client: client}
+}
+
+// Routes returns the chi sub-router mounted at /posts.
+func (rs *PostsResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	if rs.Middleware != nil {
+		r.Use(rs.Middleware)
+	}
+
+	r.Get("/", rs.List)
+	r.Post("/", rs.Create)
+
+	// Post IDs are always numeric; constraining the route param rules out
+	// path-traversal values like ".." before they ever reach a handler
+	// (notably CreateAttachment, which joins id into a filesystem path).
+	r.Get("/{id:[0-9]+}", rs.Get)
+	r.Put("/{id:[0-9]+}", rs.Update)
+	r.Patch("/{id:[0-9]+}", rs.Update)
+	r.Delete("/{id:[0-9]+}", rs.Delete)
+
+	r.Post("/{id:[0-9]+}/attachments", rs.CreateAttachment)
+
+	return r
+}
+
+func (rs *PostsResource) List(w http.ResponseWriter, r *http.Request) {
+	resp, err := rs.Client.GetPosts(r.Context())
+	if err != nil {
+		respondUpstreamError(w, "fetching posts", err)
+		return
+	}
+
+	relayResponse(w, resp)
+}
+
+func (rs *PostsResource) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp, err := rs.Client.GetPost(r.Context(), id)
+	if err != nil {
+		respondUpstreamError(w, "fetching post", err)
+		return
+	}
+
+	relayResponse(w, resp)
+}
+
+func (rs *PostsResource) Create(w http.ResponseWriter, r *http.Request) {
+	var post PostWithoutId
+	if err := decodeJSON(w, r, &post); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	body, err := json.Marshal(post)
+	if err != nil {
+		respondError(w, NewHTTPError(http.StatusInternalServerError, "encoding post"))
+		return
+	}
+
+	resp, err := rs.Client.CreatePost(r.Context(), bytes.NewReader(body))
+	if err != nil {
+		respondUpstreamError(w, "creating post", err)
+		return
+	}
+
+	relayResponse(w, resp)
+}
+
+func (rs *PostsResource) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var post PostWithoutId
+	if err := decodeJSON(w, r, &post); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	body, err := json.Marshal(post)
+	if err != nil {
+		respondError(w, NewHTTPError(http.StatusInternalServerError, "encoding post"))
+		return
+	}
+
+	resp, err := rs.Client.UpdatePost(r.Context(), id, bytes.NewReader(body))
+	if err != nil {
+		respondUpstreamError(w, "updating post", err)
+		return
+	}
+
+	relayResponse(w, resp)
+}
+
+func (rs *PostsResource) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp, err := rs.Client.DeletePost(r.Context(), id)
+	if err != nil {
+		respondUpstreamError(w, "deleting post", err)
+		return
+	}
+
+	relayResponse(w, resp)
+}
+
+// relayResponse copies an upstream *http.Response's status code and body
+// through to w with a Content-Type of application/json; the upstream's
+// other headers are not copied.
+func relayResponse(w http.ResponseWriter, resp *http.Response) {
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// respondError translates err into an HTTP response, using its status
+// code when it is an *HTTPError and falling back to 400 otherwise.
+func respondError(w http.ResponseWriter, err error) {
+	if httpErr, ok := err.(*HTTPError); ok {
+		http.Error(w, httpErr.Message, httpErr.Status)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// respondUpstreamError logs an error from the upstream Client and
+// responds with 502, since the request we received was well-formed.
+func respondUpstreamError(w http.ResponseWriter, action string, err error) {
+	log.Printf("Error %s: %v", action, err)
+	http.Error(w, "Error "+action, http.StatusBadGateway)
+}
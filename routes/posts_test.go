@@ -3,30 +3,21 @@ package routes
 import (
 	"bytes"
 	"encoding/json"
-	"io"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
-)
 
-type PostWithoutId struct {
-	UserId int
-	Title  string
-	Body   string
-}
+	"go-chi-restful/routes/routestest"
+)
 
-type Post struct {
-	Id     int
-	UserId int
-	Title  string
-	Body   string
+func newTestResource(transport *routestest.MockTransport) *PostsResource {
+	client := NewJSONPlaceholderClient(&http.Client{Transport: transport})
+	return NewPostsResource(client)
 }
 
-type JsonPlaceholderMock struct{}
+func TestGetPostsHandler(t *testing.T) {
+	t.Parallel()
 
-// mock function creates some dummy data and encodes to JSON via json.Marshal
-func (*JsonPlaceholderMock) GetPosts() (*http.Response, error) {
 	mockedPosts := []Post{{
 		Id:     1,
 		UserId: 2,
@@ -34,23 +25,11 @@ func (*JsonPlaceholderMock) GetPosts() (*http.Response, error) {
 		Body:   "Foo Bar",
 	}}
 
-	respBody, err := json.Marshal(mockedPosts)
-	if err != nil {
-		log.Panicf("Error reading mocked response data: %v", err)
-	}
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("GET", "/posts", routestest.NewJSONResponder(http.StatusOK, mockedPosts))
 
-	// then returns a minimal HTTP response with status code and body
-	return &http.Response{
-		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewBuffer(respBody)),
-	}, nil
-}
+	resource := newTestResource(transport)
 
-func TestGetPostsHandler(t *testing.T) {
-	// set GetPosts package-scoped variable to the mock function
-	GetPosts = (&JsonPlaceholderMock{}).GetPosts
-
-	// create a new GET request to send to /posts
 	req, err := http.NewRequest("GET", "/posts", nil)
 	if err != nil {
 		t.Errorf("Error creating a new request: %v", err)
@@ -58,7 +37,7 @@ func TestGetPostsHandler(t *testing.T) {
 
 	// NewRecorder records the ResponseWriter's mutations
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(PostsResource{}.List)
+	handler := http.HandlerFunc(resource.List)
 	// call the handler with the response recorder rr and created request req
 	handler.ServeHTTP(rr, req)
 	// if any error encountered, fail the test
@@ -81,45 +60,54 @@ func TestGetPostsHandler(t *testing.T) {
 	}
 }
 
-// mock the CreatePost function to avoid sending network request
-func (*JsonPlaceholderMock) CreatePost(body io.ReadCloser) (*http.Response, error) {
-	// body needs to contain ID, title, body of text
-	// since request body must be passed as type io.ReadCloser to CreatePost, it must be read into a buffer, converted into a byte slice and then decoded into a Go struct so it can be accessed normally
-	buffer := new(bytes.Buffer)
-	buffer.ReadFrom(body)
+func TestGetPostHandler(t *testing.T) {
+	t.Parallel()
+
+	mockedPost := Post{Id: 1, UserId: 2, Title: "Hello World", Body: "Foo Bar"}
 
-	var reqPost PostWithoutId
-	if err := json.Unmarshal(buffer.Bytes(), &reqPost); err != nil {
-		log.Panicf("Error decoding request body: %v", err)
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("GET", "/posts/=~^\\d+$", routestest.NewJSONResponder(http.StatusOK, mockedPost))
+
+	resource := newTestResource(transport)
+
+	req, err := http.NewRequest("GET", "/1", nil)
+	if err != nil {
+		t.Errorf("Error creating a new request: %v", err)
 	}
 
-	// when POST /posts request sent to JSONPlaceholder API, it returns a response that contains the newly created post
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
 
-	newPost := Post{
-		Id:     101,
-		UserId: reqPost.UserId,
-		Title:  reqPost.Title,
-		Body:   reqPost.Body,
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusOK, status)
 	}
 
-	// encode newPost to JSON via json.Marshal
-	respBody, err := json.Marshal(newPost)
-	if err != nil {
-		log.Panicf("Error reading mocked response data: %v", err)
+	var post Post
+	if err := json.NewDecoder(rr.Body).Decode(&post); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
 	}
 
-	// HTTP response should return with a 200 status to indicate success
-	// nopCloser returns a ReadCloser that wraps the Reader (in this case, the bytes.NewBuffer(respBody), which prepares a buffer to read respBody) with a no-op Close method, which allows the Reader to adhere to the ReadCloser interface
-	return &http.Response{
-		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewBuffer(respBody)),
-	}, nil
+	if post.Id != 1 {
+		t.Errorf("Expected post Id 1. Got: %d.", post.Id)
+	}
 }
 
 // write test for CreatePostHandler, but with adjustments for POST
 func TestCreatePost(t *testing.T) {
-	// set CreatePost package-scoped variable to mock function above
-	CreatePost = (&JsonPlaceholderMock{}).CreatePost
+	t.Parallel()
+
+	// when POST /posts request sent to JSONPlaceholder API, it returns a response that contains the newly created post
+	newPost := Post{
+		Id:     101,
+		UserId: 1,
+		Title:  "Hello World",
+		Body:   "Foo Bar",
+	}
+
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("POST", "/posts", routestest.NewJSONResponder(http.StatusOK, newPost))
+
+	resource := newTestResource(transport)
 
 	// init postWithoutId to a PostWithoutId struct
 	postWithoutId := PostWithoutId{
@@ -131,15 +119,10 @@ func TestCreatePost(t *testing.T) {
 	// encode postWithoutId to JSON via json.Marshal
 	reqBody, err := json.Marshal(postWithoutId)
 	if err != nil {
-		log.Panicf("Error reading mocked request data: %v", err)
+		t.Fatalf("Error encoding mocked request data: %v", err)
 	}
 
 	// create a new POST request via http.NewRequest
-	// because NewRequest accepts a body of type io.Reader, we must convert reqBody which is currently a byte slice to a type compatible with Reader interface, which implements a single method Read
-	// byte.NewBuffer creates and initalises a new Buffer using byte slice argument as its initial contents.
-	// Buffer type also has Read and Write methods, which matches io.Reader interface
-	// therefore, to pass reqBody to http.NewRequest as the POST request body, we must first pass it to bytes.NewBuffer and then pass the returned buffer as the request's body.
-	// request will finally then be passed to the route handler
 	req, err := http.NewRequest("POST", "/posts", bytes.NewBuffer(reqBody))
 	if err != nil {
 		t.Errorf("Error creating a new request: %v", err)
@@ -150,7 +133,7 @@ func TestCreatePost(t *testing.T) {
 
 	// like TestGetPostHandler, setup response recorder
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(PostsResource{}.Create)
+	handler := http.HandlerFunc(resource.Create)
 	handler.ServeHTTP(rr, req)
 
 	if status := rr.Code; status != http.StatusOK {
@@ -171,3 +154,107 @@ func TestCreatePost(t *testing.T) {
 		t.Errorf("Expected: %d. Got: %d.", expectedId, resultId)
 	}
 }
+
+func TestCreatePostMalformedBody(t *testing.T) {
+	t.Parallel()
+
+	resource := newTestResource(routestest.NewMockTransport())
+
+	req, err := http.NewRequest("POST", "/posts", bytes.NewBufferString("{not json"))
+	if err != nil {
+		t.Errorf("Error creating a new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	resource.Create(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusBadRequest, status)
+	}
+}
+
+func TestCreatePostWrongContentType(t *testing.T) {
+	t.Parallel()
+
+	resource := newTestResource(routestest.NewMockTransport())
+
+	postWithoutId := PostWithoutId{UserId: 1, Title: "Hello World", Body: "Foo Bar"}
+	reqBody, err := json.Marshal(postWithoutId)
+	if err != nil {
+		t.Fatalf("Error encoding mocked request data: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", "/posts", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Errorf("Error creating a new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	rr := httptest.NewRecorder()
+	resource.Create(rr, req)
+
+	if status := rr.Code; status != http.StatusUnsupportedMediaType {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusUnsupportedMediaType, status)
+	}
+}
+
+func TestUpdatePostHandler(t *testing.T) {
+	t.Parallel()
+
+	updatedPost := Post{Id: 1, UserId: 1, Title: "Updated", Body: "Foo Bar"}
+
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("PUT", "/posts/=~^\\d+$", routestest.NewJSONResponder(http.StatusOK, updatedPost))
+
+	resource := newTestResource(transport)
+
+	postWithoutId := PostWithoutId{UserId: 1, Title: "Updated", Body: "Foo Bar"}
+	reqBody, err := json.Marshal(postWithoutId)
+	if err != nil {
+		t.Fatalf("Error encoding mocked request data: %v", err)
+	}
+
+	req, err := http.NewRequest("PUT", "/1", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Errorf("Error creating a new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusOK, status)
+	}
+
+	var post Post
+	if err := json.NewDecoder(rr.Body).Decode(&post); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+
+	if post.Title != "Updated" {
+		t.Errorf("Expected post Title %q. Got: %q.", "Updated", post.Title)
+	}
+}
+
+func TestDeletePostHandler(t *testing.T) {
+	t.Parallel()
+
+	transport := routestest.NewMockTransport()
+	transport.RegisterResponder("DELETE", "/posts/=~^\\d+$", routestest.NewJSONResponder(http.StatusOK, struct{}{}))
+
+	resource := newTestResource(transport)
+
+	req, err := http.NewRequest("DELETE", "/1", nil)
+	if err != nil {
+		t.Errorf("Error creating a new request: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	resource.Routes().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusOK, status)
+	}
+}
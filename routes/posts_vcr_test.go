@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-chi-restful/routes/vcr"
+)
+
+// TestGetPostsHandlerVCR exercises List against the real JSONPlaceholder
+// client, with its RoundTripper backed by vcr so the test needs no
+// network access (and no hand-written mock) after the cassette has been
+// recorded once. Re-record it with:
+//
+//	GO_VCR=record go test ./routes/ -run TestGetPostsHandlerVCR
+func TestGetPostsHandlerVCR(t *testing.T) {
+	transport := vcr.NewTransport("testdata/get_posts.json", vcr.ModeFromEnv())
+
+	resource := NewPostsResource(NewJSONPlaceholderClient(&http.Client{Transport: transport}))
+
+	req, err := http.NewRequest("GET", "/posts", nil)
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	resource.List(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code. Expected: %d. Got: %d.", http.StatusOK, status)
+	}
+
+	var posts []Post
+	if err := json.NewDecoder(rr.Body).Decode(&posts); err != nil {
+		t.Fatalf("Error decoding response body: %v", err)
+	}
+
+	if len(posts) == 0 {
+		t.Errorf("Expected at least one post from the cassette, got none")
+	}
+}
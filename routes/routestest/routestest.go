@@ -0,0 +1,109 @@
+// Package routestest provides an httpmock-style fake http.RoundTripper so
+// tests can register canned responses by method and URL pattern instead of
+// hand-rolling a mock struct per endpoint.
+package routestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Responder builds the *http.Response returned for a matched request.
+type Responder func(*http.Request) (*http.Response, error)
+
+// NewJSONResponder returns a Responder that marshals body to JSON and
+// serves it with the given status code and an application/json
+// Content-Type.
+func NewJSONResponder(status int, body interface{}) Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader(encoded)),
+			Request:    req,
+		}
+		resp.Header.Set("Content-Type", "application/json")
+
+		return resp, nil
+	}
+}
+
+// MockTransport is an http.RoundTripper that serves registered Responders
+// instead of making network calls. It is safe for concurrent use, so it
+// can back a Client shared across t.Parallel() subtests.
+type MockTransport struct {
+	mu         sync.Mutex
+	responders []registeredResponder
+}
+
+type registeredResponder struct {
+	method    string
+	pattern   string
+	prefix    string
+	suffixRe  *regexp.Regexp
+	responder Responder
+}
+
+// NewMockTransport returns an empty MockTransport.
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// RegisterResponder registers responder to serve requests matching method
+// and urlPattern. A urlPattern containing "=~" is split into a literal
+// path prefix and a regular expression matched against the remainder of
+// the path; otherwise the pattern is matched against the path for exact
+// equality, e.g.:
+//
+//	t.RegisterResponder("GET", "/posts/=~^\\d+$", NewJSONResponder(200, post))
+//	t.RegisterResponder("GET", "/posts", NewJSONResponder(200, posts))
+func (t *MockTransport) RegisterResponder(method, urlPattern string, responder Responder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rr := registeredResponder{method: strings.ToUpper(method), pattern: urlPattern, responder: responder}
+	if prefix, expr, ok := strings.Cut(urlPattern, "=~"); ok {
+		rr.prefix = prefix
+		rr.suffixRe = regexp.MustCompile(expr)
+	}
+
+	t.responders = append(t.responders, rr)
+}
+
+// RoundTrip implements http.RoundTripper by dispatching to the first
+// registered responder matching the request's method and URL.
+func (t *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	responders := append([]registeredResponder(nil), t.responders...)
+	t.mu.Unlock()
+
+	for _, rr := range responders {
+		if rr.method != req.Method {
+			continue
+		}
+
+		if rr.suffixRe != nil {
+			if rest, ok := strings.CutPrefix(req.URL.Path, rr.prefix); ok && rr.suffixRe.MatchString(rest) {
+				return rr.responder(req)
+			}
+			continue
+		}
+
+		if rr.pattern == req.URL.Path {
+			return rr.responder(req)
+		}
+	}
+
+	return nil, fmt.Errorf("routestest: no responder registered for %s %s", req.Method, req.URL.Path)
+}
@@ -0,0 +1,58 @@
+package vcr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// interaction is one recorded request/response pair. Bodies are stored as
+// strings since the upstream JSONPlaceholder API only ever exchanges JSON
+// text; RequestBodyHash lets replay sanity-check a request matches what
+// was recorded without storing the (potentially sensitive) request body
+// itself.
+type interaction struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBodyHash string      `json:"request_body_hash"`
+
+	ResponseStatus  int         `json:"response_status"`
+	ResponseHeaders http.Header `json:"response_headers"`
+	ResponseBody    string      `json:"response_body"`
+}
+
+// cassette is the on-disk recording: an ordered list of interactions.
+// Replay serves each interaction at most once, picking the earliest
+// unconsumed one whose method, URL and request body hash match.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+func loadCassette(path string) (*cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("vcr: reading cassette %s: %w", path, err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("vcr: decoding cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+func (c *cassette) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("vcr: encoding cassette %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("vcr: writing cassette %s: %w", path, err)
+	}
+
+	return nil
+}
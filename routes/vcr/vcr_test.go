@@ -0,0 +1,106 @@
+package vcr
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestCassette(t *testing.T, dir string) string {
+	t.Helper()
+
+	c := &cassette{Interactions: []interaction{{
+		Method:          "POST",
+		URL:             "https://jsonplaceholder.typicode.com/posts",
+		RequestBodyHash: hashBody([]byte(`{"title":"recorded"}`)),
+		ResponseStatus:  http.StatusOK,
+		ResponseBody:    `{"id":1}`,
+	}}}
+
+	path := filepath.Join(dir, "cassette.json")
+	if err := c.save(path); err != nil {
+		t.Fatalf("Error saving test cassette: %v", err)
+	}
+
+	return path
+}
+
+func TestReplayMatchesRecordedRequestBody(t *testing.T) {
+	path := writeTestCassette(t, t.TempDir())
+	transport := NewTransport(path, Replay)
+
+	req, err := http.NewRequest("POST", "https://jsonplaceholder.typicode.com/posts", strings.NewReader(`{"title":"recorded"}`))
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected a matching recorded interaction, got error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200. Got: %d.", resp.StatusCode)
+	}
+}
+
+func TestReplayRejectsMismatchedRequestBody(t *testing.T) {
+	path := writeTestCassette(t, t.TempDir())
+	transport := NewTransport(path, Replay)
+
+	req, err := http.NewRequest("POST", "https://jsonplaceholder.typicode.com/posts", strings.NewReader(`{"title":"different"}`))
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("Expected replay to reject a request body that doesn't match the recorded interaction, got nil error")
+	}
+}
+
+// TestReplayOutOfOrderDoesNotStrandEarlierInteraction records two
+// interactions for the same method+URL with different bodies, then
+// replays them in the opposite order: each interaction is consumed at
+// most once rather than via a shared per-method+URL cursor, so replaying
+// body2 first must not make body1 unreachable.
+func TestReplayOutOfOrderDoesNotStrandEarlierInteraction(t *testing.T) {
+	c := &cassette{Interactions: []interaction{
+		{
+			Method:          "POST",
+			URL:             "https://jsonplaceholder.typicode.com/posts",
+			RequestBodyHash: hashBody([]byte(`{"title":"body1"}`)),
+			ResponseStatus:  http.StatusOK,
+			ResponseBody:    `{"id":1}`,
+		},
+		{
+			Method:          "POST",
+			URL:             "https://jsonplaceholder.typicode.com/posts",
+			RequestBodyHash: hashBody([]byte(`{"title":"body2"}`)),
+			ResponseStatus:  http.StatusOK,
+			ResponseBody:    `{"id":2}`,
+		},
+	}}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := c.save(path); err != nil {
+		t.Fatalf("Error saving test cassette: %v", err)
+	}
+
+	transport := NewTransport(path, Replay)
+
+	req2, err := http.NewRequest("POST", "https://jsonplaceholder.typicode.com/posts", strings.NewReader(`{"title":"body2"}`))
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+	if _, err := transport.RoundTrip(req2); err != nil {
+		t.Fatalf("Expected body2 to replay first, got error: %v", err)
+	}
+
+	req1, err := http.NewRequest("POST", "https://jsonplaceholder.typicode.com/posts", strings.NewReader(`{"title":"body1"}`))
+	if err != nil {
+		t.Fatalf("Error creating a new request: %v", err)
+	}
+	if _, err := transport.RoundTrip(req1); err != nil {
+		t.Fatalf("Expected body1 to still replay after body2 was consumed out of order, got error: %v", err)
+	}
+}